@@ -0,0 +1,145 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Config struct {
+	Extends []string            `json:"extends,omitempty"`
+	Env     map[string]EnvValue `json:"env,omitempty"`
+}
+
+// DebugString summarizes c for log lines without ever printing a Literal or
+// command-resolved value: just the extends chain and env var names.
+func (c Config) DebugString() string {
+	keys := make([]string, 0, len(c.Env))
+	for k := range c.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return fmt.Sprintf("{Extends:%v Env:%v}", c.Extends, keys)
+}
+
+//go:embed ccl.example.json
+var defaultConfigJSON []byte
+
+type Configs struct {
+	Bin         string            `json:"bin,omitempty"`
+	Default     Config            `json:"default"`
+	Configs     map[string]Config `json:"configs"`
+	EnvCommands map[string]string `json:"envCommands,omitempty"`
+
+	// envOverrides holds CCL_ENV_* values from ConfigOverrides, set by
+	// Merge. They are kept separate from Default.Env (rather than merged
+	// into it) because Default.Env is merged first in profile resolution —
+	// folding overrides in there would let a named profile's own "env"
+	// entry shadow them. Callers that compute an effective env (buildEnv,
+	// effectiveEnv) must apply envOverrides last so they always win.
+	envOverrides map[string]string
+}
+
+// Merge layers overrides on top of c and returns the result; c itself is
+// left untouched. Bin is replaced wholesale if set. Env overrides are
+// stashed in envOverrides rather than folded into Default.Env, so they are
+// applied after profile resolution and win even when the selected profile
+// sets the same key.
+func (c *Configs) Merge(overrides ConfigOverrides) *Configs {
+	merged := *c
+
+	if overrides.Bin != "" {
+		merged.Bin = overrides.Bin
+	}
+
+	if len(overrides.Env) > 0 {
+		env := make(map[string]string, len(overrides.Env))
+		for k, v := range overrides.Env {
+			env[k] = v
+		}
+		merged.envOverrides = env
+	}
+
+	return &merged
+}
+
+func getConfigPath() string {
+	if path := os.Getenv("CCL_CONFIG"); path != "" {
+		return path
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "ccl", "ccl.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
+		os.Exit(1)
+	}
+	return filepath.Join(home, ".config", "ccl", "ccl.json")
+}
+
+// Storage loads a Configs from wherever it is kept. The default is a JSON
+// file on disk, but embedders can swap in an in-memory or remote store, and
+// NewStorage picks an on-disk HCL implementation for .hcl/.hcl.json paths.
+type Storage interface {
+	// Load reads and parses the stored config. If nothing exists yet at
+	// Path(), implementations may create a starter file and return an
+	// error asking the user to edit and re-run, mirroring the original
+	// first-run behavior of ccl.
+	Load() (*Configs, error)
+	// Path returns the location Load reads from, for error messages.
+	Path() string
+}
+
+// NewStorage picks a Storage implementation based on path's extension:
+// ".hcl" and ".hcl.json" use HCLStorage, everything else uses JSONStorage.
+func NewStorage(path string) Storage {
+	if strings.HasSuffix(path, ".hcl.json") {
+		return &HCLStorage{path: path}
+	}
+	switch filepath.Ext(path) {
+	case ".hcl":
+		return &HCLStorage{path: path}
+	default:
+		return &JSONStorage{path: path}
+	}
+}
+
+// JSONStorage is the original ccl.json-on-disk storage backend.
+type JSONStorage struct {
+	path string
+}
+
+func (s *JSONStorage) Path() string { return s.path }
+
+func (s *JSONStorage) Load() (*Configs, error) {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		configDir := filepath.Dir(s.path)
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			return nil, fmt.Errorf("error creating config directory %s: %v", configDir, err)
+		}
+
+		if err := os.WriteFile(s.path, defaultConfigJSON, 0600); err != nil {
+			return nil, fmt.Errorf("error writing config file %s: %v", s.path, err)
+		}
+		fmt.Printf("Created default config at %s\n", s.path)
+		fmt.Println("Please edit the config file to add your API keys")
+		return nil, fmt.Errorf("config file created at %s, please edit it and run again", s.path)
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %v", err)
+	}
+
+	var configs Configs
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("error parsing config: %v", err)
+	}
+
+	return &configs, nil
+}
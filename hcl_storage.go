@@ -0,0 +1,82 @@
+//go:build hcl
+
+// HCL config support pulls in github.com/hashicorp/hcl/v2 and its transitive
+// dependencies (zclconf/go-cty, agext/levenshtein, apparentlymart/go-textseg,
+// mitchellh/go-wordwrap, …). That's a heavy addition for an optional alternate
+// config format, so it's opt-in: build with `-tags hcl` to get a real
+// HCLStorage; the default (tagless) build links hcl_storage_stub.go instead,
+// which keeps ccl dependency-free but fails ".hcl"/".hcl.json" paths with a
+// clear error telling the user how to get HCL support.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclsimple"
+)
+
+// HCLStorage reads ccl config from an HCL file instead of JSON, for users
+// who prefer HCL's comment and heredoc support (credit to zk's ParseConfig
+// for the shape of decoding into a parallel struct rather than fighting the
+// JSON tags above). Unlike JSONStorage it never creates a starter file,
+// since there's no natural default to embed in two formats at once.
+type HCLStorage struct {
+	path string
+}
+
+func (s *HCLStorage) Path() string { return s.path }
+
+// hclConfigs mirrors Configs/Config but with hcl tags; the JSON-tagged
+// structs stay the canonical in-memory representation so the rest of ccl
+// never needs to know which storage backend loaded them.
+type hclConfigs struct {
+	Bin         string            `hcl:"bin,optional"`
+	EnvCommands map[string]string `hcl:"envCommands,optional"`
+	Default     *hclConfig        `hcl:"default,block"`
+	Configs     []hclConfig       `hcl:"config,block"`
+}
+
+type hclConfig struct {
+	Name string            `hcl:"name,label"`
+	Env  map[string]string `hcl:"env,optional"`
+}
+
+func (s *HCLStorage) Load() (*Configs, error) {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("hcl config %s does not exist; create it (ccl cannot generate an HCL starter file)", s.path)
+	}
+
+	var raw hclConfigs
+	if err := hclsimple.DecodeFile(s.path, nil, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing hcl config: %w", err)
+	}
+
+	configs := &Configs{
+		Bin:         raw.Bin,
+		EnvCommands: raw.EnvCommands,
+		Configs:     make(map[string]Config, len(raw.Configs)),
+	}
+	if raw.Default != nil {
+		configs.Default = Config{Env: literalEnv(raw.Default.Env)}
+	}
+	for _, c := range raw.Configs {
+		configs.Configs[c.Name] = Config{Env: literalEnv(c.Env)}
+	}
+
+	return configs, nil
+}
+
+// literalEnv wraps plain strings as EnvValue; HCL configs don't support the
+// command-resolution shorthand that JSON's "$exec:" string form does, since
+// hcl:"env" here is decoded straight into map[string]string.
+func literalEnv(env map[string]string) map[string]EnvValue {
+	if env == nil {
+		return nil
+	}
+	result := make(map[string]EnvValue, len(env))
+	for k, v := range env {
+		result[k] = EnvValue{Literal: v}
+	}
+	return result
+}
@@ -0,0 +1,19 @@
+//go:build !hcl
+
+package main
+
+import "fmt"
+
+// HCLStorage stands in for the real HCL backend (hcl_storage.go, built with
+// `-tags hcl`) in the default build, so ccl stays dependency-free unless a
+// user actually asks for HCL support. Load always fails with a clear error;
+// Path still returns the configured path, as the Storage interface requires.
+type HCLStorage struct {
+	path string
+}
+
+func (s *HCLStorage) Path() string { return s.path }
+
+func (s *HCLStorage) Load() (*Configs, error) {
+	return nil, fmt.Errorf("%s: HCL config support was not compiled in; rebuild ccl with -tags hcl", s.path)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"strings"
+)
+
+// LogLevel is a severity threshold, modeled on consul-template's
+// logging.Setup: only messages at or below the configured level are
+// emitted.
+type LogLevel int
+
+const (
+	LogLevelError LogLevel = iota
+	LogLevelWarn
+	LogLevelInfo
+	LogLevelDebug
+	LogLevelTrace
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelError:
+		return "ERROR"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelTrace:
+		return "TRACE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseLogLevel parses the -log-level flag value, accepting any case.
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToUpper(s) {
+	case "ERROR":
+		return LogLevelError, nil
+	case "WARN", "WARNING":
+		return LogLevelWarn, nil
+	case "INFO":
+		return LogLevelInfo, nil
+	case "DEBUG":
+		return LogLevelDebug, nil
+	case "TRACE":
+		return LogLevelTrace, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q (want error|warn|info|debug|trace)", s)
+	}
+}
+
+// Logger is a small leveled logger that writes to a single io.Writer, which
+// may be stderr or a syslog connection. Its Env helpers are the only path
+// env values should take to a log line, so a key matched by isSensitiveKey
+// is always redacted regardless of call site or log level.
+type Logger struct {
+	level  LogLevel
+	writer io.Writer
+}
+
+func NewLogger(level LogLevel, writer io.Writer) *Logger {
+	return &Logger{level: level, writer: writer}
+}
+
+// syslogFacilities mirrors consul-template's -syslog-facility flag.
+var syslogFacilities = map[string]syslog.Priority{
+	"kern": syslog.LOG_KERN, "user": syslog.LOG_USER, "mail": syslog.LOG_MAIL,
+	"daemon": syslog.LOG_DAEMON, "auth": syslog.LOG_AUTH, "syslog": syslog.LOG_SYSLOG,
+	"lpr": syslog.LOG_LPR, "news": syslog.LOG_NEWS, "uucp": syslog.LOG_UUCP,
+	"cron": syslog.LOG_CRON, "authpriv": syslog.LOG_AUTHPRIV, "ftp": syslog.LOG_FTP,
+	"local0": syslog.LOG_LOCAL0, "local1": syslog.LOG_LOCAL1, "local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3, "local4": syslog.LOG_LOCAL4, "local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6, "local7": syslog.LOG_LOCAL7,
+}
+
+// NewSyslogLogger connects to the local syslog daemon under the given
+// facility name (e.g. "local0", "user") instead of writing to stderr.
+func NewSyslogLogger(level LogLevel, facility string) (*Logger, error) {
+	priority, ok := syslogFacilities[strings.ToLower(facility)]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+	sink, err := syslog.New(priority, "ccl")
+	if err != nil {
+		return nil, fmt.Errorf("connecting to syslog: %w", err)
+	}
+	return NewLogger(level, sink), nil
+}
+
+func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+	if level > l.level {
+		return
+	}
+	fmt.Fprintf(l.writer, "[%s] %s\n", level, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Error(format string, args ...interface{}) { l.log(LogLevelError, format, args...) }
+func (l *Logger) Warn(format string, args ...interface{})  { l.log(LogLevelWarn, format, args...) }
+func (l *Logger) Info(format string, args ...interface{})  { l.log(LogLevelInfo, format, args...) }
+func (l *Logger) Debug(format string, args ...interface{}) { l.log(LogLevelDebug, format, args...) }
+func (l *Logger) Trace(format string, args ...interface{}) { l.log(LogLevelTrace, format, args...) }
+
+// DebugEnv logs key=value at debug level under the given prefix (e.g.
+// "default env", "selected env"), redacting value whenever isSensitiveKey
+// matches key.
+func (l *Logger) DebugEnv(prefix, key, value string) {
+	if isSensitiveKey(key) {
+		l.Debug("%s: %s=***masked***", prefix, key)
+	} else {
+		l.Debug("%s: %s=%s", prefix, key, value)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"error", LogLevelError, false},
+		{"ERROR", LogLevelError, false},
+		{"warn", LogLevelWarn, false},
+		{"warning", LogLevelWarn, false},
+		{"info", LogLevelInfo, false},
+		{"debug", LogLevelDebug, false},
+		{"trace", LogLevelTrace, false},
+		{"bogus", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLogLevel(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseLogLevel(%q) = nil error, want error", tt.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLogLevel(%q) = %v, want no error", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLogLevel(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerDebugEnvRedactsSensitiveKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LogLevelDebug, &buf)
+
+	logger.DebugEnv("selected env", "ANTHROPIC_API_KEY", "sk-super-secret")
+	logger.DebugEnv("selected env", "ANTHROPIC_BASE_URL", "https://example.com")
+
+	out := buf.String()
+	if strings.Contains(out, "sk-super-secret") {
+		t.Errorf("DebugEnv leaked a sensitive value into the log: %s", out)
+	}
+	if !strings.Contains(out, "ANTHROPIC_API_KEY=***masked***") {
+		t.Errorf("DebugEnv did not mask sensitive key, got: %s", out)
+	}
+	if !strings.Contains(out, "ANTHROPIC_BASE_URL=https://example.com") {
+		t.Errorf("DebugEnv masked a non-sensitive key, got: %s", out)
+	}
+}
+
+func TestLoggerRespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LogLevelWarn, &buf)
+
+	logger.Debug("should not appear")
+	logger.Warn("should appear")
+
+	out := buf.String()
+	if strings.Contains(out, "should not appear") {
+		t.Errorf("Debug logged below configured level, got: %s", out)
+	}
+	if !strings.Contains(out, "should appear") {
+		t.Errorf("Warn did not log at configured level, got: %s", out)
+	}
+}
@@ -1,77 +1,23 @@
 package main
 
 import (
-	_ "embed"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 )
 
-type Config struct {
-	Env map[string]string `json:"env,omitempty"`
-}
-
-//go:embed ccl.example.json
-var defaultConfigJSON []byte
-
-type Configs struct {
-	Bin     string            `json:"bin,omitempty"`
-	Default Config            `json:"default"`
-	Configs map[string]Config `json:"configs"`
-}
-
 type FlagOptions struct {
-	Yolo    bool
-	Verbose bool
-	Help    bool
-}
-
-func getConfigPath() string {
-	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
-		return filepath.Join(xdg, "ccl", "ccl.json")
-	}
-	home, err := os.UserHomeDir()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
-		os.Exit(1)
-	}
-	return filepath.Join(home, ".config", "ccl", "ccl.json")
-}
-
-func loadConfigs() (*Configs, error) {
-	configPath := getConfigPath()
-
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		configDir := filepath.Dir(configPath)
-		if err := os.MkdirAll(configDir, 0755); err != nil {
-			return nil, fmt.Errorf("error creating config directory %s: %v", configDir, err)
-		}
-
-		if err := os.WriteFile(configPath, defaultConfigJSON, 0600); err != nil {
-			return nil, fmt.Errorf("error writing config file %s: %v", configPath, err)
-		}
-		fmt.Printf("Created default config at %s\n", configPath)
-		fmt.Println("Please edit the config file to add your API keys")
-		return nil, fmt.Errorf("config file created at %s, please edit it and run again", configPath)
-	}
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("error reading config: %v", err)
-	}
-
-	var configs Configs
-	if err := json.Unmarshal(data, &configs); err != nil {
-		return nil, fmt.Errorf("error parsing config: %v", err)
-	}
-
-	return &configs, nil
+	Yolo           bool
+	Verbose        bool
+	Help           bool
+	Supervise      bool
+	RestartBackoff time.Duration
+	LogLevel       string
+	Syslog         string
 }
 
 // setTerminalTitle sets the terminal window title to show the current config.
@@ -114,10 +60,14 @@ func parseArgs(args []string) (*FlagOptions, []string, error) {
 	opts := &FlagOptions{}
 	fs.BoolVar(&opts.Yolo, "yolo", false, "enable yolo mode")
 	fs.BoolVar(&opts.Yolo, "y", false, "alias for -yolo")
-	fs.BoolVar(&opts.Verbose, "verbose", false, "enable verbose logging")
+	fs.BoolVar(&opts.Verbose, "verbose", false, "enable verbose logging (sugar for -log-level=debug)")
 	fs.BoolVar(&opts.Help, "help", false, "show help message")
 	fs.BoolVar(&opts.Help, "h", false, "alias for -help")
-	
+	fs.BoolVar(&opts.Supervise, "supervise", false, "run claude as a supervised child, reloading config on SIGHUP instead of exec-replacing ccl")
+	fs.DurationVar(&opts.RestartBackoff, "restart-backoff", time.Second, "delay before respawning claude after a SIGHUP-triggered restart")
+	fs.StringVar(&opts.LogLevel, "log-level", "", "log level: error|warn|info|debug|trace (default info, or debug with -verbose)")
+	fs.StringVar(&opts.Syslog, "syslog", "", "send logs to syslog under this facility (e.g. local0) instead of stderr")
+
 	// Parse the provided args instead of os.Args
 	err := fs.Parse(args)
 	if err != nil {
@@ -129,9 +79,21 @@ func parseArgs(args []string) (*FlagOptions, []string, error) {
 }
 
 
+// loadEffectiveConfigs loads Configs from storage and layers environment
+// overrides (CCL_BIN, CCL_ENV_*) on top, so every call site sees the same
+// effective configuration regardless of storage backend.
+func loadEffectiveConfigs(storage Storage) (*Configs, error) {
+	configs, err := storage.Load()
+	if err != nil {
+		return nil, err
+	}
+	return configs.Merge(LoadOverridesFromEnv()), nil
+}
+
 func main() {
 	startTime := time.Now()
 	configPath := getConfigPath()
+	storage := NewStorage(configPath)
 
 	// Parse arguments - handle special subcommands first
 	args := os.Args[1:]
@@ -147,7 +109,7 @@ func main() {
 	// Handle special subcommands
 	if args[0] == "list" {
 		// Load configurations for listing
-		configs, err := loadConfigs()
+		configs, err := loadEffectiveConfigs(storage)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -160,7 +122,41 @@ func main() {
 		}
 		os.Exit(0)
 	}
-	
+
+	if args[0] == "show" {
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "Usage: %s show <config-name>\n", os.Args[0])
+			os.Exit(1)
+		}
+		configs, err := loadEffectiveConfigs(storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitCodeConfigError)
+		}
+		if err := runShow(configs, args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if args[0] == "diff" {
+		if len(args) != 3 {
+			fmt.Fprintf(os.Stderr, "Usage: %s diff <config-a> <config-b>\n", os.Args[0])
+			os.Exit(1)
+		}
+		configs, err := loadEffectiveConfigs(storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitCodeConfigError)
+		}
+		if err := runDiff(configs, args[1], args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Parse config name from first argument
 	configName := args[0]
 	argsForParsing := args[1:] // remaining args after config name
@@ -172,21 +168,45 @@ func main() {
 		os.Exit(1)
 	}
 
-	if opts.Verbose {
-		fmt.Printf("Using config: %s\n", configPath)
-		fmt.Printf("Initial args: %v\n", os.Args[1:])
-		fmt.Printf("Config name: %s\n", configName)
-		fmt.Printf("Args for parsing: %v\n", argsForParsing)
-		fmt.Printf("Parsed flags: yolo=%v, verbose=%v, help=%v\n",
-			opts.Yolo, opts.Verbose, opts.Help)
-		fmt.Printf("Remaining args: %v\n", remainingArgs)
+	// -verbose is sugar for -log-level=debug when -log-level wasn't set
+	// explicitly; an explicit -log-level always wins.
+	levelStr := opts.LogLevel
+	if levelStr == "" {
+		if opts.Verbose {
+			levelStr = "debug"
+		} else {
+			levelStr = "info"
+		}
+	}
+	level, err := ParseLogLevel(levelStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitCodeConfigError)
+	}
+
+	var logger *Logger
+	if opts.Syslog != "" {
+		logger, err = NewSyslogLogger(level, opts.Syslog)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitCodeConfigError)
+		}
+	} else {
+		logger = NewLogger(level, os.Stderr)
 	}
 
+	logger.Debug("Using config: %s", configPath)
+	logger.Debug("Initial args: %v", os.Args[1:])
+	logger.Debug("Config name: %s", configName)
+	logger.Debug("Args for parsing: %v", argsForParsing)
+	logger.Debug("Parsed flags: yolo=%v, verbose=%v, help=%v", opts.Yolo, opts.Verbose, opts.Help)
+	logger.Debug("Remaining args: %v", remainingArgs)
+
 	// Load configurations
-	configs, err := loadConfigs()
+	configs, err := loadEffectiveConfigs(storage)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(ExitCodeConfigError)
 	}
 
 	// Handle help flag
@@ -195,30 +215,37 @@ func main() {
 		fmt.Fprintf(os.Stderr, "       %s list\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\nSubcommands:\n")
 		fmt.Fprintf(os.Stderr, "  list                 list available configurations\n")
+		fmt.Fprintf(os.Stderr, "  show <name>          print the fully-resolved effective config as JSON\n")
+		fmt.Fprintf(os.Stderr, "  diff <a> <b>         compare the effective env of two configs\n")
 		fmt.Fprintf(os.Stderr, "\nArguments:\n")
 		fmt.Fprintf(os.Stderr, "  <config-name>        configuration name to use (required)\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		fmt.Fprintf(os.Stderr, "  -yolo, -y            enable yolo mode\n")
-		fmt.Fprintf(os.Stderr, "  -verbose             enable verbose logging\n")
+		fmt.Fprintf(os.Stderr, "  -verbose             enable verbose logging (sugar for -log-level=debug)\n")
+		fmt.Fprintf(os.Stderr, "  -log-level           log level: error|warn|info|debug|trace\n")
+		fmt.Fprintf(os.Stderr, "  -syslog              send logs to syslog under this facility instead of stderr\n")
+		fmt.Fprintf(os.Stderr, "  -supervise           run claude as a supervised child, reloading config on SIGHUP\n")
+		fmt.Fprintf(os.Stderr, "  -restart-backoff     delay before respawning claude after a reload restart\n")
 		fmt.Fprintf(os.Stderr, "  -help, -h            show help message\n")
 		fmt.Fprintf(os.Stderr, "\nOther options are passed through to claude command\n")
 		os.Exit(0)
 	}
 
 
-	// Select config
+	// Select config, resolving its "extends" chain (if any) on top of Default
 	var selectedConfig Config
 	if configName == "default" {
 		selectedConfig = configs.Default
-		if opts.Verbose {
-			fmt.Printf("Using default config: %+v\n", selectedConfig)
-		}
+		logger.Debug("Using default config: %s", selectedConfig.DebugString())
 	} else {
-		if config, exists := configs.Configs[configName]; exists {
-			selectedConfig = config
-			if opts.Verbose {
-				fmt.Printf("Selected config: %+v\n", selectedConfig)
+		if _, exists := configs.Configs[configName]; exists {
+			resolved, err := resolveNamed(configs, configName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error resolving config %q: %v\n", configName, err)
+				os.Exit(1)
 			}
+			selectedConfig = resolved
+			logger.Debug("Selected config: %s", selectedConfig.DebugString())
 		} else {
 			fmt.Fprintf(os.Stderr, "Config '%s' not found\n", configName)
 			fmt.Fprintf(os.Stderr, "Available configurations:\n")
@@ -234,76 +261,21 @@ func main() {
 	// Build transformed args
 	var transformedArgs []string
 	if opts.Yolo {
-		if opts.Verbose {
-			fmt.Println("Transforming --yolo to --dangerously-skip-permissions")
-		}
+		logger.Debug("Transforming --yolo to --dangerously-skip-permissions")
 		transformedArgs = append(transformedArgs, "--dangerously-skip-permissions")
 	}
 
 	// Add remaining arguments (config name was already consumed)
 	transformedArgs = append(transformedArgs, remainingArgs...)
 
-	if opts.Verbose {
-		fmt.Printf("Final selected config: %+v\n", selectedConfig)
-		fmt.Printf("Config name: '%s'\n", configName)
-		fmt.Printf("Transformed args: %v\n", transformedArgs)
-	}
-
-	// Build environment from a map to avoid duplicates
-	envMap := make(map[string]string)
-
-	// Start with current environment
-	for _, envVar := range os.Environ() {
-		if parts := strings.SplitN(envVar, "=", 2); len(parts) == 2 {
-			envMap[parts[0]] = parts[1]
-		}
-	}
+	logger.Debug("Final selected config: %s", selectedConfig.DebugString())
+	logger.Debug("Config name: '%s'", configName)
+	logger.Debug("Transformed args: %v", transformedArgs)
 
-	originalCount := len(envMap)
-	if opts.Verbose {
-		fmt.Printf("Original env count: %d\n", originalCount)
-	}
-
-	// Merge default.Env first
-	if configs.Default.Env != nil {
-		for key, value := range configs.Default.Env {
-			envMap[key] = value
-			if opts.Verbose {
-				if isSensitiveKey(key) {
-					fmt.Printf("Added default env var: %s=***masked***\n", key)
-				} else {
-					fmt.Printf("Added default env var: %s=%s\n", key, value)
-				}
-			}
-		}
-	}
-
-	// Then merge selected config env (overrides default)
-	if selectedConfig.Env != nil {
-		for key, value := range selectedConfig.Env {
-			envMap[key] = value
-			if opts.Verbose {
-				if isSensitiveKey(key) {
-					fmt.Printf("Added selected env var: %s=***masked***\n", key)
-				} else {
-					fmt.Printf("Added selected env var: %s=%s\n", key, value)
-				}
-			}
-		}
-	} else {
-		if opts.Verbose {
-			fmt.Println("No environment variables configured in selected config")
-		}
-	}
-
-	// Convert map back to []string
-	env := make([]string, 0, len(envMap))
-	for key, value := range envMap {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
-	}
-
-	if opts.Verbose {
-		fmt.Printf("Final env count: %d (added %d)\n", len(env), len(env)-originalCount)
+	env, err := buildEnv(configs, selectedConfig, configName, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
 	// Set terminal title based on selected config
@@ -316,9 +288,7 @@ func main() {
 	if configs.Bin != "" {
 		// Use configured binary path
 		claudePath = configs.Bin
-		if opts.Verbose {
-			fmt.Printf("Using configured binary path: %s\n", claudePath)
-		}
+		logger.Debug("Using configured binary path: %s", claudePath)
 	} else {
 		// Fall back to exec.LookPath
 		var err error
@@ -330,26 +300,29 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Add: \"bin\": \"/path/to/claude\"\n")
 			os.Exit(1)
 		}
-		if opts.Verbose {
-			fmt.Printf("Found claude in PATH: %s\n", claudePath)
-		}
+		logger.Debug("Found claude in PATH: %s", claudePath)
 	}
 
 	lookPathTime := time.Since(lookPathStart)
-	if opts.Verbose {
-		fmt.Printf("Binary resolution time: %v\n", lookPathTime)
-	}
+	logger.Debug("Binary resolution time: %v", lookPathTime)
 
 	execTime := time.Since(startTime)
 
 	// Execute directly: claude args...
 	finalArgs := append([]string{"claude"}, transformedArgs...)
-	if opts.Verbose {
-		fmt.Printf("Executing: %s with args %v\n", claudePath, finalArgs)
-	}
-
-	if opts.Verbose {
-		fmt.Printf("ccl startup time: %v\n", execTime)
+	logger.Debug("Executing: %s with args %v", claudePath, finalArgs)
+	logger.Debug("ccl startup time: %v", execTime)
+
+	if opts.Supervise {
+		os.Exit(runSupervised(supervisorOptions{
+			storage:        storage,
+			configName:     configName,
+			claudePath:     claudePath,
+			args:           transformedArgs,
+			env:            env,
+			restartBackoff: opts.RestartBackoff,
+			logger:         logger,
+		}))
 	}
 
 	err = syscall.Exec(claudePath, finalArgs, env)
@@ -358,3 +331,60 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// buildEnv resolves configs.EnvCommands then selectedConfig.Env (which wins
+// on conflicts) on top of the current process environment, running any
+// command-backed secrets exactly once along the way, then applies
+// configs.envOverrides (CCL_ENV_*) last so a CI/Nix injection always wins
+// even when the selected profile sets the same key. selectedConfig is
+// expected to already be the fully-resolved effective config for the
+// selection (resolveNamed folds Default.Env and any "extends" chain into
+// it), so Default.Env itself is never resolved separately here.
+func buildEnv(configs *Configs, selectedConfig Config, configName string, logger *Logger) ([]string, error) {
+	envMap := make(map[string]string)
+
+	for _, envVar := range os.Environ() {
+		if parts := strings.SplitN(envVar, "=", 2); len(parts) == 2 {
+			envMap[parts[0]] = parts[1]
+		}
+	}
+
+	originalCount := len(envMap)
+	logger.Debug("Original env count: %d", originalCount)
+
+	sharedEnv, err := resolveEnv(configs.EnvCommands, nil)
+	if err != nil {
+		return nil, fmt.Errorf("resolving envCommands: %w", err)
+	}
+	for key, value := range sharedEnv {
+		envMap[key] = value
+		logger.DebugEnv("Added shared env var", key, value)
+	}
+
+	if selectedConfig.Env != nil {
+		selectedEnv, err := resolveEnv(nil, selectedConfig.Env)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s env: %w", configName, err)
+		}
+		for key, value := range selectedEnv {
+			envMap[key] = value
+			logger.DebugEnv("Added selected env var", key, value)
+		}
+	} else {
+		logger.Debug("No environment variables configured in selected config")
+	}
+
+	for key, value := range configs.envOverrides {
+		envMap[key] = value
+		logger.DebugEnv("Added CCL_ENV_ override", key, value)
+	}
+
+	env := make([]string, 0, len(envMap))
+	for key, value := range envMap {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	logger.Debug("Final env count: %d (added %d)", len(env), len(env)-originalCount)
+
+	return env, nil
+}
@@ -183,15 +183,24 @@ func TestParseArgsErrorHandling(t *testing.T) {
 }
 
 func TestGetConfigPathXDG(t *testing.T) {
-	// Save original env vars
+	// Save original env vars. CCL_CONFIG takes priority over XDG_CONFIG_HOME
+	// in getConfigPath, so it must be cleared here too or a CCL_CONFIG set in
+	// the real environment would silently short-circuit every case below.
 	origXDG := os.Getenv("XDG_CONFIG_HOME")
+	origCCLConfig := os.Getenv("CCL_CONFIG")
 	defer func() {
 		if origXDG == "" {
 			os.Unsetenv("XDG_CONFIG_HOME")
 		} else {
 			os.Setenv("XDG_CONFIG_HOME", origXDG)
 		}
+		if origCCLConfig == "" {
+			os.Unsetenv("CCL_CONFIG")
+		} else {
+			os.Setenv("CCL_CONFIG", origCCLConfig)
+		}
 	}()
+	os.Unsetenv("CCL_CONFIG")
 
 	tests := []struct {
 		name        string
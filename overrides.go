@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// envOverridePrefix marks environment variables that force a single env var
+// to a given value no matter which profile is selected, e.g.
+// CCL_ENV_ANTHROPIC_API_KEY=sk-... sets ANTHROPIC_API_KEY without touching
+// ccl.json.
+const envOverridePrefix = "CCL_ENV_"
+
+// ConfigOverrides are values layered on top of a file-loaded Configs, so CI
+// systems and Nix shells can inject or replace values without editing
+// ccl.json. They come from environment variables: CCL_BIN overrides Bin,
+// and each CCL_ENV_<NAME> overrides the effective env var NAME regardless
+// of which profile is selected (applied last, after profile resolution, so
+// a profile's own "env" entry can never shadow it — see Configs.Merge).
+// CCL_CONFIG (handled in getConfigPath) selects which config file to load in
+// the first place, rather than being part of this struct.
+type ConfigOverrides struct {
+	Bin string
+	Env map[string]string
+}
+
+// LoadOverridesFromEnv reads CCL_BIN and CCL_ENV_* from the process
+// environment.
+func LoadOverridesFromEnv() ConfigOverrides {
+	overrides := ConfigOverrides{Env: make(map[string]string)}
+
+	overrides.Bin = os.Getenv("CCL_BIN")
+
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, envOverridePrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, envOverridePrefix)
+		if name == "" {
+			continue
+		}
+		overrides.Env[name] = value
+	}
+
+	return overrides
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigsMerge(t *testing.T) {
+	base := &Configs{
+		Bin: "claude",
+		Default: Config{
+			Env: map[string]EnvValue{
+				"ANTHROPIC_API_KEY": {Literal: "file-key"},
+				"OTHER_VAR":         {Literal: "file-other"},
+			},
+		},
+		Configs: map[string]Config{},
+	}
+
+	merged := base.Merge(ConfigOverrides{
+		Bin: "claude-custom",
+		Env: map[string]string{"ANTHROPIC_API_KEY": "override-key"},
+	})
+
+	if merged.Bin != "claude-custom" {
+		t.Errorf("Bin = %q, want %q", merged.Bin, "claude-custom")
+	}
+	// Env overrides must NOT be folded into Default.Env: a named profile's
+	// own "env" entry is merged in after Default.Env during resolution, and
+	// would otherwise silently shadow the override.
+	if got := merged.Default.Env["ANTHROPIC_API_KEY"].Literal; got != "file-key" {
+		t.Errorf("Merge must not touch Default.Env[ANTHROPIC_API_KEY], got %q, want %q", got, "file-key")
+	}
+	if got := merged.envOverrides["ANTHROPIC_API_KEY"]; got != "override-key" {
+		t.Errorf("envOverrides[ANTHROPIC_API_KEY] = %q, want %q", got, "override-key")
+	}
+	if got := merged.Default.Env["OTHER_VAR"].Literal; got != "file-other" {
+		t.Errorf("OTHER_VAR = %q, want %q (should survive untouched)", got, "file-other")
+	}
+
+	// The original must be left untouched.
+	if base.Bin != "claude" {
+		t.Errorf("base.Bin mutated to %q, want %q", base.Bin, "claude")
+	}
+	if base.envOverrides != nil {
+		t.Errorf("base.envOverrides mutated to %v, want nil", base.envOverrides)
+	}
+}
+
+func TestConfigsMergeNoOverrides(t *testing.T) {
+	base := &Configs{Bin: "claude", Default: Config{Env: map[string]EnvValue{"A": {Literal: "a"}}}}
+	merged := base.Merge(ConfigOverrides{})
+
+	if merged.Bin != "claude" {
+		t.Errorf("Bin = %q, want %q", merged.Bin, "claude")
+	}
+	if got := merged.Default.Env["A"].Literal; got != "a" {
+		t.Errorf("Default.Env[A] = %q, want %q", got, "a")
+	}
+}
+
+// TestConfigsMergeOverridesWinOverNamedProfile pins the exact scenario from
+// the request: CCL_ENV_ANTHROPIC_API_KEY must still win even when the
+// selected profile sets its own ANTHROPIC_API_KEY.
+func TestConfigsMergeOverridesWinOverNamedProfile(t *testing.T) {
+	configs := &Configs{
+		Default: Config{Env: map[string]EnvValue{"ANTHROPIC_API_KEY": {Literal: "default-key"}}},
+		Configs: map[string]Config{
+			"prod": {Env: map[string]EnvValue{"ANTHROPIC_API_KEY": {Literal: "prod-key"}}},
+		},
+	}
+	merged := configs.Merge(ConfigOverrides{Env: map[string]string{"ANTHROPIC_API_KEY": "injected-key"}})
+
+	cfg, err := resolveNamed(merged, "prod")
+	if err != nil {
+		t.Fatalf("resolveNamed() = %v, want no error", err)
+	}
+	// The profile's own entry still wins over Default during resolution...
+	if got := cfg.Env["ANTHROPIC_API_KEY"].Literal; got != "prod-key" {
+		t.Fatalf("resolved profile ANTHROPIC_API_KEY = %q, want %q", got, "prod-key")
+	}
+	// ...but buildEnv's final overlay of envOverrides must still win overall.
+	env, err := buildEnv(merged, cfg, "prod", NewLogger(LogLevelError, discardWriter{}))
+	if err != nil {
+		t.Fatalf("buildEnv() = %v, want no error", err)
+	}
+	if got := lookupEnv(env, "ANTHROPIC_API_KEY"); got != "injected-key" {
+		t.Errorf("buildEnv ANTHROPIC_API_KEY = %q, want %q (CCL_ENV_ override must win over the selected profile)", got, "injected-key")
+	}
+}
+
+// discardWriter is a no-op io.Writer for tests that need a *Logger but don't
+// care about its output.
+type discardWriter struct{}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+// lookupEnv finds "KEY=value" in an os.Environ()-style slice and returns
+// value, or "" if KEY is absent.
+func lookupEnv(env []string, key string) string {
+	prefix := key + "="
+	for _, kv := range env {
+		if strings.HasPrefix(kv, prefix) {
+			return strings.TrimPrefix(kv, prefix)
+		}
+	}
+	return ""
+}
+
+func TestLoadOverridesFromEnv(t *testing.T) {
+	t.Setenv("CCL_BIN", "claude-env")
+	t.Setenv("CCL_ENV_ANTHROPIC_API_KEY", "env-key")
+	t.Setenv("CCL_ENV_", "should be ignored")
+
+	overrides := LoadOverridesFromEnv()
+
+	if overrides.Bin != "claude-env" {
+		t.Errorf("Bin = %q, want %q", overrides.Bin, "claude-env")
+	}
+	if got := overrides.Env["ANTHROPIC_API_KEY"]; got != "env-key" {
+		t.Errorf("Env[ANTHROPIC_API_KEY] = %q, want %q", got, "env-key")
+	}
+	if _, ok := overrides.Env[""]; ok {
+		t.Error("CCL_ENV_ with an empty name should not produce an override")
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// resolveNamed looks up name (which may be "default") and, for named
+// profiles, resolves its "extends" chain into a single effective Config.
+func resolveNamed(configs *Configs, name string) (Config, error) {
+	if name == "default" {
+		return configs.Default, nil
+	}
+	if _, exists := configs.Configs[name]; !exists {
+		return Config{}, fmt.Errorf("config %q not found", name)
+	}
+	return resolveProfile(configs, name, map[string]Config{}, nil)
+}
+
+// resolveProfile merges Default.Env, then each parent in cfg.Extends
+// left-to-right, then cfg's own Env on top (so later extends and the
+// profile's own entries win on conflicts) into a single effective Config.
+// This replaces the old two-level default+selected merge in main with
+// arbitrary-depth inheritance. cache memoizes already-resolved profiles;
+// path tracks the current inheritance chain so a cycle produces a clear
+// error listing the chain instead of recursing forever.
+func resolveProfile(configs *Configs, name string, cache map[string]Config, path []string) (Config, error) {
+	if cached, ok := cache[name]; ok {
+		return cached, nil
+	}
+	for _, p := range path {
+		if p == name {
+			return Config{}, fmt.Errorf("cycle detected in profile inheritance: %s -> %s", strings.Join(path, " -> "), name)
+		}
+	}
+
+	cfg, exists := configs.Configs[name]
+	if !exists {
+		return Config{}, fmt.Errorf("config %q not found", name)
+	}
+	childPath := append(append([]string{}, path...), name)
+
+	merged := Config{Env: map[string]EnvValue{}}
+	for k, v := range configs.Default.Env {
+		merged.Env[k] = v
+	}
+
+	for _, parent := range cfg.Extends {
+		var parentConfig Config
+		var err error
+		if parent == "default" {
+			parentConfig = configs.Default
+		} else {
+			parentConfig, err = resolveProfile(configs, parent, cache, childPath)
+			if err != nil {
+				return Config{}, err
+			}
+		}
+		for k, v := range parentConfig.Env {
+			merged.Env[k] = v
+		}
+	}
+
+	for k, v := range cfg.Env {
+		merged.Env[k] = v
+	}
+
+	cache[name] = merged
+	return merged, nil
+}
+
+// effectiveConfigView is the JSON shape printed by `ccl show`.
+type effectiveConfigView struct {
+	Bin string            `json:"bin,omitempty"`
+	Env map[string]string `json:"env"`
+}
+
+// effectiveEnv resolves name's inheritance chain and masks sensitive keys,
+// so `ccl show` never prints a resolved secret to the terminal (`ccl diff`
+// uses resolveEffectiveEnv and masks its own output instead). Command-backed secrets are only executed for non-sensitive keys;
+// a sensitive key is masked without ever running its command, since these
+// subcommands are read-only display and have no use for the real value.
+func effectiveEnv(configs *Configs, name string) (map[string]string, error) {
+	cfg, err := resolveNamed(configs, name)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make(map[string]string, len(configs.EnvCommands))
+	for k, v := range configs.EnvCommands {
+		if !isSensitiveKey(k) {
+			commands[k] = v
+		}
+	}
+	entries := make(map[string]EnvValue, len(cfg.Env))
+	for k, v := range cfg.Env {
+		if !isSensitiveKey(k) {
+			entries[k] = v
+		}
+	}
+	resolved, err := resolveEnv(commands, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	masked := make(map[string]string, len(resolved)+len(configs.EnvCommands)+len(cfg.Env))
+	for k, v := range resolved {
+		masked[k] = v
+	}
+	for k := range configs.EnvCommands {
+		if isSensitiveKey(k) {
+			masked[k] = "***masked***"
+		}
+	}
+	for k := range cfg.Env {
+		if isSensitiveKey(k) {
+			masked[k] = "***masked***"
+		}
+	}
+	for k, v := range configs.envOverrides {
+		masked[k] = maskIfSensitive(k, v)
+	}
+	return masked, nil
+}
+
+// runShow prints name's fully-resolved effective config as JSON.
+func runShow(configs *Configs, name string) error {
+	env, err := effectiveEnv(configs, name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(effectiveConfigView{Bin: configs.Bin, Env: env}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// resolveEffectiveEnv resolves name's inheritance chain and every
+// command-backed secret to its real value, unmasked, then applies
+// configs.envOverrides (CCL_ENV_*) last so it always wins, matching
+// buildEnv. Unlike effectiveEnv, this runs commands for sensitive keys too,
+// since runDiff needs the real values to tell whether two profiles
+// actually differ; callers are responsible for masking before printing
+// anything this returns.
+func resolveEffectiveEnv(configs *Configs, name string) (map[string]string, error) {
+	cfg, err := resolveNamed(configs, name)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveEnv(configs.EnvCommands, cfg.Env)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range configs.envOverrides {
+		resolved[k] = v
+	}
+	return resolved, nil
+}
+
+// maskIfSensitive returns "***masked***" for a sensitive key, or value
+// unchanged otherwise.
+func maskIfSensitive(key, value string) string {
+	if isSensitiveKey(key) {
+		return "***masked***"
+	}
+	return value
+}
+
+// runDiff prints a stable, sorted key-by-key comparison of the effective
+// env for profiles a and b. Comparison is done on real resolved values so
+// that two sensitive keys with different secrets are never reported as
+// equal; only the printed output is masked.
+func runDiff(configs *Configs, a, b string) error {
+	envA, err := resolveEffectiveEnv(configs, a)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", a, err)
+	}
+	envB, err := resolveEffectiveEnv(configs, b)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", b, err)
+	}
+
+	keys := make(map[string]struct{}, len(envA)+len(envB))
+	for k := range envA {
+		keys[k] = struct{}{}
+	}
+	for k := range envB {
+		keys[k] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, k := range sortedKeys {
+		va, inA := envA[k]
+		vb, inB := envB[k]
+		switch {
+		case inA && inB && va == vb:
+			fmt.Printf("  %s=%s\n", k, maskIfSensitive(k, va))
+		case inA && inB && isSensitiveKey(k):
+			fmt.Printf("~ %s differs (%s, %s)\n", k, a, b)
+		case inA && inB:
+			fmt.Printf("- %s=%s (%s)\n", k, va, a)
+			fmt.Printf("+ %s=%s (%s)\n", k, vb, b)
+		case inA:
+			fmt.Printf("- %s=%s (%s)\n", k, maskIfSensitive(k, va), a)
+		case inB:
+			fmt.Printf("+ %s=%s (%s)\n", k, maskIfSensitive(k, vb), b)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestResolveProfileMergeOrder(t *testing.T) {
+	configs := &Configs{
+		Default: Config{Env: map[string]EnvValue{
+			"A": {Literal: "default-a"},
+			"B": {Literal: "default-b"},
+		}},
+		Configs: map[string]Config{
+			"base": {Env: map[string]EnvValue{
+				"B": {Literal: "base-b"},
+				"C": {Literal: "base-c"},
+			}},
+			"child": {
+				Extends: []string{"base"},
+				Env: map[string]EnvValue{
+					"C": {Literal: "child-c"},
+				},
+			},
+		},
+	}
+
+	resolved, err := resolveNamed(configs, "child")
+	if err != nil {
+		t.Fatalf("resolveNamed() = %v, want no error", err)
+	}
+
+	want := map[string]string{"A": "default-a", "B": "base-b", "C": "child-c"}
+	for k, v := range want {
+		if got := resolved.Env[k].Literal; got != v {
+			t.Errorf("Env[%s] = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestResolveProfileCycleDetection(t *testing.T) {
+	configs := &Configs{
+		Configs: map[string]Config{
+			"a": {Extends: []string{"b"}},
+			"b": {Extends: []string{"a"}},
+		},
+	}
+
+	_, err := resolveNamed(configs, "a")
+	if err == nil {
+		t.Fatal("resolveNamed() on a cyclic extends chain = nil error, want error")
+	}
+}
+
+func TestResolveProfileDiamondInheritance(t *testing.T) {
+	configs := &Configs{
+		Configs: map[string]Config{
+			"base":  {Env: map[string]EnvValue{"X": {Literal: "base-x"}}},
+			"left":  {Extends: []string{"base"}, Env: map[string]EnvValue{"Y": {Literal: "left-y"}}},
+			"right": {Extends: []string{"base"}, Env: map[string]EnvValue{"Z": {Literal: "right-z"}}},
+			"diamond": {
+				Extends: []string{"left", "right"},
+			},
+		},
+	}
+
+	resolved, err := resolveNamed(configs, "diamond")
+	if err != nil {
+		t.Fatalf("resolveNamed() = %v, want no error (diamond inheritance should not be flagged as a cycle)", err)
+	}
+	want := map[string]string{"X": "base-x", "Y": "left-y", "Z": "right-z"}
+	for k, v := range want {
+		if got := resolved.Env[k].Literal; got != v {
+			t.Errorf("Env[%s] = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestResolveProfileNotFound(t *testing.T) {
+	configs := &Configs{Configs: map[string]Config{}}
+	if _, err := resolveNamed(configs, "missing"); err == nil {
+		t.Fatal("resolveNamed() for an unknown config = nil error, want error")
+	}
+}
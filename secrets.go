@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// defaultSecretCommandTimeout bounds how long ccl will wait for an external
+// secret-fetching command (e.g. `op read`) before giving up.
+const defaultSecretCommandTimeout = 5 * time.Second
+
+// EnvValue is an env entry in ccl.json. It unmarshals from a plain string
+// (the literal value, stored as-is) or from an object of the form
+// {"command": ["op", "read", "op://vault/claude/api_key"], "timeout": "5s"},
+// which is resolved at load time by running the command and capturing its
+// trimmed stdout. The shorthand "$exec: op read op://vault/claude/api_key"
+// is equivalent to the object form with the default timeout.
+type EnvValue struct {
+	Literal string
+	Command []string
+	Timeout time.Duration
+}
+
+func (v *EnvValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if strings.HasPrefix(s, "$exec:") {
+			fields := strings.Fields(strings.TrimPrefix(s, "$exec:"))
+			if len(fields) == 0 {
+				return fmt.Errorf("env value %q must set a command after \"$exec:\"", s)
+			}
+			v.Command = fields
+			v.Timeout = defaultSecretCommandTimeout
+			return nil
+		}
+		v.Literal = s
+		return nil
+	}
+
+	var obj struct {
+		Command []string `json:"command"`
+		Timeout string   `json:"timeout"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("env value must be a string or a {command, timeout} object: %w", err)
+	}
+	if len(obj.Command) == 0 {
+		return fmt.Errorf("env value object must set a non-empty \"command\"")
+	}
+	v.Command = obj.Command
+	v.Timeout = defaultSecretCommandTimeout
+	if obj.Timeout != "" {
+		d, err := time.ParseDuration(obj.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", obj.Timeout, err)
+		}
+		v.Timeout = d
+	}
+	return nil
+}
+
+// Resolve returns the value for this entry, running its command (if any)
+// with a bounded timeout and capturing trimmed stdout. key is only used to
+// produce a clear error message.
+func (v EnvValue) Resolve(key string) (string, error) {
+	if len(v.Command) == 0 {
+		return v.Literal, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, v.Command[0], v.Command[1:]...)
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("resolving env var %s: command %q timed out after %s", key, strings.Join(v.Command, " "), v.Timeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolving env var %s: command %q failed: %w", key, strings.Join(v.Command, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// resolveEnv runs the command associated with each entry (if any) and
+// returns a plain string map, merging commands in the top-level EnvCommands
+// table before the per-entry values so a selected config can still override
+// a shared command-backed secret.
+func resolveEnv(commands map[string]string, entries map[string]EnvValue) (map[string]string, error) {
+	resolved := make(map[string]string, len(commands)+len(entries))
+
+	for key, commandLine := range commands {
+		fields := strings.Fields(commandLine)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("envCommands entry %s must set a non-empty command", key)
+		}
+		value := EnvValue{Command: fields, Timeout: defaultSecretCommandTimeout}
+		v, err := value.Resolve(key)
+		if err != nil {
+			return nil, err
+		}
+		resolved[key] = v
+	}
+
+	for key, entry := range entries {
+		v, err := entry.Resolve(key)
+		if err != nil {
+			return nil, err
+		}
+		resolved[key] = v
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import "testing"
+
+func TestEnvValueUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		json        string
+		wantLiteral string
+		wantCommand []string
+		wantErr     bool
+	}{
+		{
+			name:        "literal string",
+			json:        `"sk-abc123"`,
+			wantLiteral: "sk-abc123",
+		},
+		{
+			name:        "exec shorthand",
+			json:        `"$exec: op read op://vault/claude/api_key"`,
+			wantCommand: []string{"op", "read", "op://vault/claude/api_key"},
+		},
+		{
+			name:    "exec shorthand with no command",
+			json:    `"$exec:"`,
+			wantErr: true,
+		},
+		{
+			name:    "exec shorthand with only whitespace",
+			json:    `"$exec:   "`,
+			wantErr: true,
+		},
+		{
+			name:        "object form",
+			json:        `{"command": ["op", "read", "op://vault/claude/api_key"], "timeout": "10s"}`,
+			wantCommand: []string{"op", "read", "op://vault/claude/api_key"},
+		},
+		{
+			name:    "object form with empty command",
+			json:    `{"command": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "object form with invalid timeout",
+			json:    `{"command": ["op", "read"], "timeout": "not-a-duration"}`,
+			wantErr: true,
+		},
+		{
+			name:    "neither string nor object",
+			json:    `123`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v EnvValue
+			err := v.UnmarshalJSON([]byte(tt.json))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("UnmarshalJSON(%q) = nil error, want error", tt.json)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON(%q) = %v, want no error", tt.json, err)
+			}
+			if v.Literal != tt.wantLiteral {
+				t.Errorf("Literal = %q, want %q", v.Literal, tt.wantLiteral)
+			}
+			if len(v.Command) != len(tt.wantCommand) {
+				t.Fatalf("Command = %v, want %v", v.Command, tt.wantCommand)
+			}
+			for i := range v.Command {
+				if v.Command[i] != tt.wantCommand[i] {
+					t.Errorf("Command[%d] = %q, want %q", i, v.Command[i], tt.wantCommand[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolveEnvRejectsEmptyEnvCommand(t *testing.T) {
+	_, err := resolveEnv(map[string]string{"API_KEY": "   "}, nil)
+	if err == nil {
+		t.Fatal("resolveEnv with a blank envCommands entry = nil error, want error")
+	}
+}
+
+func TestResolveEnvLiteral(t *testing.T) {
+	resolved, err := resolveEnv(nil, map[string]EnvValue{"API_KEY": {Literal: "sk-abc123"}})
+	if err != nil {
+		t.Fatalf("resolveEnv() = %v, want no error", err)
+	}
+	if resolved["API_KEY"] != "sk-abc123" {
+		t.Errorf("resolved[API_KEY] = %q, want %q", resolved["API_KEY"], "sk-abc123")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Exit codes mirroring consul-replicate's CLI so shell wrappers can tell a
+// bad config apart from an interrupted run or a claude crash. main returns
+// ExitCodeConfigError when config or logging setup fails in any mode;
+// runSupervised returns ExitCodeInterrupt and ExitCodeRunnerError.
+const (
+	ExitCodeConfigError = 10
+	ExitCodeInterrupt   = 130
+	ExitCodeRunnerError = 11
+)
+
+// supervisorOptions bundles what runSupervised needs to (re)launch claude
+// and reload its config on SIGHUP.
+type supervisorOptions struct {
+	storage        Storage
+	configName     string
+	claudePath     string
+	args           []string
+	env            []string
+	restartBackoff time.Duration
+	logger         *Logger
+}
+
+// runSupervised runs claude as a child process (rather than exec-replacing
+// ccl) and reloads ccl.json on SIGHUP, modeled on consul-replicate's
+// CLI.Run reload path: keep the env the child was started with, rebuild it
+// from storage via reloadEnv on SIGHUP, diff the two, and only restart the
+// child if it actually changed.
+func runSupervised(opts supervisorOptions) int {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	env := opts.env
+	proc, waitCh, err := spawnClaude(opts.claudePath, opts.args, env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting claude: %v\n", err)
+		return ExitCodeRunnerError
+	}
+	opts.logger.Info("supervising claude (pid %d), send SIGHUP to %d to reload config", proc.Pid, os.Getpid())
+
+	for {
+		select {
+		case sig := <-sigCh:
+			switch sig {
+			case syscall.SIGHUP:
+				newEnv, err := reloadEnv(opts.storage, opts.configName, opts.logger)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "ccl: error reloading config, keeping current claude running: %v\n", err)
+					continue
+				}
+				if envEqual(env, newEnv) {
+					opts.logger.Info("config reloaded, env unchanged, leaving claude running")
+					continue
+				}
+				opts.logger.Info("config reloaded, env changed, restarting claude")
+				proc.Signal(syscall.SIGTERM)
+				<-waitCh
+				time.Sleep(opts.restartBackoff)
+				env = newEnv
+				proc, waitCh, err = spawnClaude(opts.claudePath, opts.args, env)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error restarting claude: %v\n", err)
+					return ExitCodeRunnerError
+				}
+			case syscall.SIGINT, syscall.SIGTERM:
+				proc.Signal(sig)
+				<-waitCh
+				return ExitCodeInterrupt
+			}
+		case waitErr := <-waitCh:
+			if waitErr == nil {
+				return 0
+			}
+			if exitErr, ok := waitErr.(*exec.ExitError); ok {
+				return exitErr.ExitCode()
+			}
+			fmt.Fprintf(os.Stderr, "Error running claude: %v\n", waitErr)
+			return ExitCodeRunnerError
+		}
+	}
+}
+
+// spawnClaude forks claude with stdio forwarded to ccl's own, returning the
+// running process and a channel that receives its Wait() result exactly
+// once.
+func spawnClaude(path string, args []string, env []string) (*os.Process, chan error, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- cmd.Wait() }()
+
+	return cmd.Process, waitCh, nil
+}
+
+// reloadEnv re-reads config from storage and rebuilds the effective env for
+// configName, the same way main() does on startup.
+func reloadEnv(storage Storage, configName string, logger *Logger) ([]string, error) {
+	configs, err := loadEffectiveConfigs(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedConfig, err := resolveNamed(configs, configName)
+	if err != nil {
+		return nil, fmt.Errorf("config %q: %w", configName, err)
+	}
+
+	return buildEnv(configs, selectedConfig, configName, logger)
+}
+
+// envEqual reports whether two process env slices ("KEY=VALUE" entries)
+// hold the same set of entries, ignoring order.
+func envEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]struct{}, len(a))
+	for _, entry := range a {
+		set[entry] = struct{}{}
+	}
+	for _, entry := range b {
+		if _, ok := set[entry]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestEnvEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want bool
+	}{
+		{"identical", []string{"A=1", "B=2"}, []string{"A=1", "B=2"}, true},
+		{"reordered", []string{"A=1", "B=2"}, []string{"B=2", "A=1"}, true},
+		{"different value", []string{"A=1"}, []string{"A=2"}, false},
+		{"different length", []string{"A=1"}, []string{"A=1", "B=2"}, false},
+		{"both empty", nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := envEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("envEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}